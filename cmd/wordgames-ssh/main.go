@@ -0,0 +1,114 @@
+// Command wordgames-ssh serves the word-game client over SSH, so players
+// can join with `ssh host -p 2200` instead of installing the local binary.
+// Each connecting session gets its own model and its own websocket
+// connection to the game backend.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/PixelSam123/wordgamestui/tui"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	bm "github.com/charmbracelet/wish/bubbletea"
+	"github.com/charmbracelet/wish/logging"
+	"github.com/muesli/termenv"
+)
+
+func main() {
+	host := flag.String("host", "localhost", "address to listen on")
+	port := flag.String("port", "2200", "port to listen on")
+	hostKeyPath := flag.String("host-key-path", ".ssh/wordgames_ed25519", "path to the SSH host key")
+	gameURL := flag.String(
+		"game-url",
+		"wss://mc.chenk.my.id:3000/ws/anagram/1",
+		"websocket URL of the game backend each session connects to",
+	)
+	timestamps := flag.String("timestamps", "off", "chat timestamp display mode for every session: off, short, or full")
+	tzName := flag.String("tz", "", "IANA timezone name to display timestamps in (default: local)")
+	flag.Parse()
+
+	timestampMode, err := tui.ParseTimestampMode(*timestamps)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	tz := time.Local
+	if *tzName != "" {
+		loc, err := time.LoadLocation(*tzName)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		tz = loc
+	}
+
+	s, err := wish.NewServer(
+		wish.WithAddress(net.JoinHostPort(*host, *port)),
+		wish.WithHostKeyPath(*hostKeyPath),
+		wish.WithMiddleware(
+			bm.MiddlewareWithColorProfile(teaHandler(*gameURL, timestampMode, tz), termenv.TrueColor),
+			logging.Middleware(),
+		),
+	)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	done := make(chan os.Signal, 1)
+	signal.Notify(done, os.Interrupt, syscall.SIGTERM)
+
+	log.Printf("starting SSH server on %s against %s", s.Addr, *gameURL)
+
+	go func() {
+		if err := s.ListenAndServe(); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
+			log.Fatalln(err)
+		}
+	}()
+
+	<-done
+	log.Println("stopping SSH server")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := s.Shutdown(ctx); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// teaHandler builds a per-session model connected to gameURL, with the
+// remote username as the initial nick. It captures the *tea.Program that
+// wish's bubbletea middleware constructs from the returned options, so the
+// model's background websocket goroutines can deliver messages into it.
+// The model is built against s.Context(), which wish cancels as soon as the
+// session ends for any reason (clean exit, dropped connection, server
+// shutdown) — without this, wish's bubbletea middleware never gives the
+// model a chance to react to the session closing, and the per-session
+// websocket connection and goroutines would leak for the life of the process.
+func teaHandler(gameURL string, timestampMode tui.TimestampMode, tz *time.Location) bm.Handler {
+	return func(s ssh.Session) (tea.Model, []tea.ProgramOption) {
+		var p *tea.Program
+
+		m := tui.NewModel(s.Context(), func(msg tea.Msg) {
+			if p != nil {
+				p.Send(msg)
+			}
+		}, gameURL, s.User(), timestampMode, tz)
+
+		return m, []tea.ProgramOption{
+			tea.WithAltScreen(),
+			tea.ProgramOption(func(program *tea.Program) {
+				p = program
+			}),
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Package common holds the websocket message contracts shared between the
+// client and the game server, so both sides can agree on one definition
+// instead of each side re-deriving it from ad-hoc map access.
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SchemaVersion is the message schema version this client was built
+// against. Bump it whenever a breaking change lands in the contracts below.
+const SchemaVersion = 1
+
+// Envelope is the outer shape of every websocket frame. Content is decoded
+// lazily so the type switch can happen before committing to a concrete type.
+type Envelope struct {
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schema_version"`
+	Content       json.RawMessage `json:"content"`
+}
+
+// ChatMessage is a plain chat line relayed to every player in the room. The
+// server sends it as a bare JSON string rather than an object.
+type ChatMessage struct {
+	Content string
+}
+
+func (c *ChatMessage) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &c.Content)
+}
+
+// OngoingRoundInfo announces the word to guess and when the round ends.
+type OngoingRoundInfo struct {
+	WordToGuess     string    `json:"word_to_guess"`
+	RoundFinishTime time.Time `json:"round_finish_time"`
+}
+
+// FinishedRoundInfo announces the round's answer and when the next round starts.
+type FinishedRoundInfo struct {
+	WordAnswer      string    `json:"word_answer"`
+	ToNextRoundTime time.Time `json:"to_next_round_time"`
+}
+
+// FinishedGame signals the game has ended. It carries no payload.
+type FinishedGame struct{}
+
+// Pong answers a client ping. It carries no payload.
+type Pong struct{}
+
+// ErrSchemaVersionMismatch is returned when the server declares a schema
+// version this client wasn't built to understand.
+type ErrSchemaVersionMismatch struct {
+	Got  int
+	Want int
+}
+
+func (e ErrSchemaVersionMismatch) Error() string {
+	return fmt.Sprintf("schema version mismatch: server sent %d, client expects %d", e.Got, e.Want)
+}
+
+// CheckSchemaVersion validates a received schema version against
+// SchemaVersion. A zero version is treated as "unversioned" and allowed, so
+// older servers that predate this field still work.
+func CheckSchemaVersion(got int) error {
+	if got != 0 && got != SchemaVersion {
+		return ErrSchemaVersionMismatch{Got: got, Want: SchemaVersion}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// envelopeFixtures mirrors the frames the game server actually sends, one
+// per message type, recorded so a round trip through Envelope and the
+// per-type struct can be checked without a live backend.
+var envelopeFixtures = []string{
+	`{"type":"ChatMessage","schema_version":1,"content":"hello room"}`,
+	`{"type":"OngoingRoundInfo","schema_version":1,"content":{"word_to_guess":"gopher","round_finish_time":"2026-07-28T10:00:00Z"}}`,
+	`{"type":"FinishedRoundInfo","schema_version":1,"content":{"word_answer":"gopher","to_next_round_time":"2026-07-28T10:00:05Z"}}`,
+	`{"type":"FinishedGame","schema_version":1,"content":{}}`,
+	`{"type":"PongMessage","schema_version":1,"content":{}}`,
+}
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	for _, raw := range envelopeFixtures {
+		var env Envelope
+		if err := json.Unmarshal([]byte(raw), &env); err != nil {
+			t.Fatalf("json.Unmarshal Envelope: %v", err)
+		}
+
+		if err := CheckSchemaVersion(env.SchemaVersion); err != nil {
+			t.Fatalf("CheckSchemaVersion: %v", err)
+		}
+
+		switch env.Type {
+		case "ChatMessage":
+			var content ChatMessage
+			if err := json.Unmarshal(env.Content, &content); err != nil {
+				t.Fatalf("json.Unmarshal ChatMessage: %v", err)
+			}
+			if content.Content != "hello room" {
+				t.Errorf("ChatMessage.Content = %q, want %q", content.Content, "hello room")
+			}
+
+		case "OngoingRoundInfo":
+			var content OngoingRoundInfo
+			if err := json.Unmarshal(env.Content, &content); err != nil {
+				t.Fatalf("json.Unmarshal OngoingRoundInfo: %v", err)
+			}
+			if content.WordToGuess != "gopher" {
+				t.Errorf("OngoingRoundInfo.WordToGuess = %q, want %q", content.WordToGuess, "gopher")
+			}
+			want := time.Date(2026, 7, 28, 10, 0, 0, 0, time.UTC)
+			if !content.RoundFinishTime.Equal(want) {
+				t.Errorf("OngoingRoundInfo.RoundFinishTime = %v, want %v", content.RoundFinishTime, want)
+			}
+
+		case "FinishedRoundInfo":
+			var content FinishedRoundInfo
+			if err := json.Unmarshal(env.Content, &content); err != nil {
+				t.Fatalf("json.Unmarshal FinishedRoundInfo: %v", err)
+			}
+			if content.WordAnswer != "gopher" {
+				t.Errorf("FinishedRoundInfo.WordAnswer = %q, want %q", content.WordAnswer, "gopher")
+			}
+
+		case "FinishedGame":
+			var content FinishedGame
+			if err := json.Unmarshal(env.Content, &content); err != nil {
+				t.Fatalf("json.Unmarshal FinishedGame: %v", err)
+			}
+
+		case "PongMessage":
+			var content Pong
+			if err := json.Unmarshal(env.Content, &content); err != nil {
+				t.Fatalf("json.Unmarshal Pong: %v", err)
+			}
+
+		default:
+			t.Fatalf("unhandled fixture type: %s", env.Type)
+		}
+	}
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	if err := CheckSchemaVersion(0); err != nil {
+		t.Errorf("CheckSchemaVersion(0) = %v, want nil (unversioned server)", err)
+	}
+
+	if err := CheckSchemaVersion(SchemaVersion); err != nil {
+		t.Errorf("CheckSchemaVersion(%d) = %v, want nil", SchemaVersion, err)
+	}
+
+	if err := CheckSchemaVersion(SchemaVersion + 1); err == nil {
+		t.Errorf("CheckSchemaVersion(%d) = nil, want mismatch error", SchemaVersion+1)
+	}
+}
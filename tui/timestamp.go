@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimestampMode controls whether, and how, chat lines are prefixed with a
+// timestamp in View.
+type TimestampMode int
+
+const (
+	TimestampOff TimestampMode = iota
+	TimestampShort
+	TimestampFull
+)
+
+// ParseTimestampMode parses the --timestamps flag value.
+func ParseTimestampMode(s string) (TimestampMode, error) {
+	switch s {
+	case "off":
+		return TimestampOff, nil
+	case "short":
+		return TimestampShort, nil
+	case "full":
+		return TimestampFull, nil
+	default:
+		return TimestampOff, fmt.Errorf("invalid timestamp mode %q: want off, short, or full", s)
+	}
+}
+
+// Next cycles to the following mode, wrapping from full back to off. Bound
+// to Ctrl+T so players can toggle timestamps without restarting.
+func (m TimestampMode) Next() TimestampMode {
+	return (m + 1) % 3
+}
+
+// prefix formats t for display according to m, including trailing
+// separator space, or "" when timestamps are off.
+func (m TimestampMode) prefix(t time.Time) string {
+	switch m {
+	case TimestampShort:
+		return t.Format("15:04 ")
+	case TimestampFull:
+		return t.Format("2006-01-02 15:04:05 ")
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,686 @@
+// Package tui holds the bubbletea model that drives the word-game client,
+// shared between the local CLI binary and the SSH front-end so both can
+// host the same game against any websocket URL.
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PixelSam123/wordgamestui/common"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/timer"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+const defaultWidth = 56
+const defaultMessagesHeight = 12
+
+// nonMessageLines is how many lines of chrome (status line, header, word
+// box, borders, input, hotkeys) surround the message viewport, used to size
+// it from a tea.WindowSizeMsg.
+const nonMessageLines = 11
+
+type initConnMsg struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	conn   *websocket.Conn
+}
+type successSentMsg struct{}
+type errMsg struct{ err error }
+
+type wsChatMsg struct{ content string }
+type wsOngoingRoundInfoMsg struct{ content common.OngoingRoundInfo }
+type wsFinishedRoundInfoMsg struct{ content common.FinishedRoundInfo }
+type wsFinishedGameMsg struct{}
+type wsPongMsg struct{}
+type wsErrMsg struct{ err error }
+
+// chatLine is one line of scrollback: the text the server or a local
+// command produced, and when it arrived.
+type chatLine struct {
+	ts   time.Time
+	text string
+}
+
+// Model is the bubbletea model for one player's session of the game.
+type Model struct {
+	parentCtx     context.Context
+	ctx           context.Context
+	cancel        context.CancelFunc
+	conn          *websocket.Conn
+	send          func(tea.Msg)
+	err           error
+	timer         timer.Model
+	textInput     textinput.Model
+	viewport      viewport.Model
+	messages      []chatLine
+	messageCache  []string
+	focusMessages bool
+	width         int
+	wordBoxGuide  string
+	wordBox       string
+	wsURL         string
+	room          string
+	nick          string
+	showHelp      bool
+	timestampMode TimestampMode
+	tz            *time.Location
+}
+
+// NewModel builds the initial state for a new session against wsURL. ctx
+// bounds the session's lifetime: every websocket connection the model opens
+// is cancelled along with it, so passing an ssh.Session's context lets an
+// SSH front-end tear down a dropped session's socket and goroutines instead
+// of leaking them. send is the Program's Send method, used by background
+// goroutines to deliver websocket events back into the Update loop. nick, if
+// non-empty, is sent to the server as soon as the connection is established.
+// timestampMode and tz control how chat lines are prefixed in View.
+func NewModel(ctx context.Context, send func(tea.Msg), wsURL string, nick string, timestampMode TimestampMode, tz *time.Location) Model {
+	ti := textinput.New()
+	ti.Placeholder = "connecting..."
+	ti.Focus()
+	ti.Width = defaultWidth - 2
+
+	vp := viewport.New(defaultWidth-2, defaultMessagesHeight)
+
+	return Model{
+		parentCtx:     ctx,
+		send:          send,
+		textInput:     ti,
+		viewport:      vp,
+		width:         defaultWidth,
+		wordBoxGuide:  "WAITING ROUND START!",
+		wsURL:         wsURL,
+		room:          roomFromURL(wsURL),
+		nick:          nick,
+		timestampMode: timestampMode,
+		tz:            tz,
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(tea.ClearScreen, textinput.Blink, connectToWsServer(m.parentCtx, m.wsURL))
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			if m.cancel != nil {
+				m.cancel()
+			}
+			return m, tea.Quit
+
+		case "tab":
+			m.focusMessages = !m.focusMessages
+
+			if m.focusMessages {
+				m.textInput.Blur()
+			} else {
+				m.textInput.Focus()
+			}
+
+		case "ctrl+e":
+			if !m.focusMessages {
+				m.err = nil
+			}
+
+		case "ctrl+t":
+			m.timestampMode = m.timestampMode.Next()
+			m.rewrapMessages()
+
+		case "pgup":
+			if m.focusMessages {
+				m.viewport.ViewUp()
+			}
+
+		case "pgdown":
+			if m.focusMessages {
+				m.viewport.ViewDown()
+			}
+
+		case "home":
+			if m.focusMessages {
+				m.viewport.GotoTop()
+			}
+
+		case "end":
+			if m.focusMessages {
+				m.viewport.GotoBottom()
+			}
+
+		case "enter":
+			if m.focusMessages {
+				break
+			}
+
+			trimmedInput := strings.TrimSpace(m.textInput.Value())
+
+			if strings.HasPrefix(trimmedInput, "/") {
+				fields := strings.Fields(trimmedInput)
+				name := strings.TrimPrefix(fields[0], "/")
+
+				if cmd, ok := commands[name]; ok {
+					cmds = append(cmds, cmd(&m, fields[1:]))
+					m.textInput.SetValue("")
+				} else {
+					m.err = fmt.Errorf("unknown command: /%s", name)
+				}
+			} else if m.conn != nil && trimmedInput != "" {
+				cmds = append(cmds, sendToWsServer(m.ctx, m.conn, trimmedInput))
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+
+		innerWidth := max(20, m.width-2)
+		m.textInput.Width = innerWidth
+		m.viewport.Width = innerWidth
+		m.viewport.Height = max(3, msg.Height-nonMessageLines)
+
+		m.rewrapMessages()
+
+	case timer.TickMsg:
+		var cmd tea.Cmd
+
+		m.timer, cmd = m.timer.Update(msg)
+		cmds = append(cmds, cmd)
+
+	case initConnMsg:
+		m.textInput.Placeholder = "message/answer here, send with Enter"
+		m.ctx = msg.ctx
+		m.cancel = msg.cancel
+		m.conn = msg.conn
+
+		go receiveFromWsServer(m.ctx, m.conn, m.send)
+		go periodicallyPingWsServer(m.ctx, m.conn, m.send)
+
+		if m.nick != "" {
+			cmds = append(cmds, sendToWsServer(m.ctx, m.conn, "/nick "+m.nick))
+		}
+
+	case errMsg:
+		m.err = msg.err
+
+	case successSentMsg:
+		m.textInput.SetValue("")
+
+	case wsPongMsg:
+		// nothing to do, the read loop already keeps listening
+
+	case wsErrMsg:
+		m.err = msg.err
+
+	case wsChatMsg:
+		m.appendMessage(msg.content)
+
+	case wsOngoingRoundInfoMsg:
+		m.wordBoxGuide = "PLEASE GUESS!"
+		m.wordBox = msg.content.WordToGuess
+
+		m.timer = timer.NewWithInterval(time.Until(msg.content.RoundFinishTime), 100*time.Millisecond)
+
+		cmds = append(cmds, m.timer.Init())
+
+	case wsFinishedRoundInfoMsg:
+		m.wordBoxGuide = "TIME'S UP! THE ANSWER:"
+		m.wordBox = msg.content.WordAnswer
+
+		m.timer = timer.NewWithInterval(time.Until(msg.content.ToNextRoundTime), 100*time.Millisecond)
+
+		cmds = append(cmds, m.timer.Init())
+
+	case wsFinishedGameMsg:
+		m.wordBoxGuide = "WAITING ROUND START!"
+		m.wordBox = ""
+	}
+
+	var cmd tea.Cmd
+
+	if m.focusMessages {
+		m.viewport, cmd = m.viewport.Update(msg)
+	} else {
+		m.textInput, cmd = m.textInput.Update(msg)
+	}
+	cmds = append(cmds, cmd)
+
+	return m, tea.Batch(cmds...)
+}
+
+// appendMessage adds text to the scrollback, re-wraps it for the current
+// viewport width and timestamp mode, and scrolls to the bottom to reveal it.
+func (m *Model) appendMessage(text string) {
+	ts := time.Now()
+	if m.tz != nil {
+		ts = ts.In(m.tz)
+	}
+
+	line := chatLine{ts, text}
+	m.messages = append(m.messages, line)
+	m.messageCache = append(m.messageCache, m.formatLine(line))
+	m.viewport.SetContent(strings.Join(m.messageCache, "\n"))
+	m.viewport.GotoBottom()
+}
+
+// rewrapMessages re-renders the full scrollback for the current viewport
+// width and timestamp mode. Call on resize or timestamp mode change;
+// appendMessage handles the common case of a single new message without
+// re-rendering everything else.
+func (m *Model) rewrapMessages() {
+	m.messageCache = make([]string, len(m.messages))
+	for i, line := range m.messages {
+		m.messageCache[i] = m.formatLine(line)
+	}
+
+	m.viewport.SetContent(strings.Join(m.messageCache, "\n"))
+}
+
+// formatLine prefixes line with a timestamp per m.timestampMode and wraps
+// it to the current viewport width.
+func (m *Model) formatLine(line chatLine) string {
+	return wordwrap.String(m.timestampMode.prefix(line.ts)+line.text, m.viewport.Width)
+}
+
+// helpEntry documents one slash command for the /help panel.
+type helpEntry struct {
+	usage       string
+	description string
+}
+
+var helpEntries = []helpEntry{
+	{"/help", "toggle this help panel"},
+	{"/nick <name>", "set your display nick"},
+	{"/rooms", "note that no room listing is available"},
+	{"/join <room>", "switch to another room"},
+	{"/who", "show your current nick and room"},
+	{"/history <n>", "scroll back n lines through the chat"},
+	{"/clear", "clear the chat area"},
+	{"/exit", "quit the client"},
+}
+
+// commands dispatches slash-command names (without the leading "/") to their handlers.
+var commands = map[string]func(m *Model, args []string) tea.Cmd{
+	"help":    cmdHelp,
+	"nick":    cmdNick,
+	"rooms":   cmdRooms,
+	"join":    cmdJoin,
+	"who":     cmdWho,
+	"history": cmdHistory,
+	"clear":   cmdClear,
+	"exit":    cmdExit,
+}
+
+func cmdHelp(m *Model, args []string) tea.Cmd {
+	m.showHelp = !m.showHelp
+
+	return nil
+}
+
+func cmdNick(m *Model, args []string) tea.Cmd {
+	if len(args) < 1 {
+		m.err = fmt.Errorf("/nick requires a name")
+		return nil
+	}
+
+	m.nick = args[0]
+
+	if m.conn == nil {
+		return nil
+	}
+
+	return sendToWsServer(m.ctx, m.conn, "/nick "+m.nick)
+}
+
+func cmdRooms(m *Model, args []string) tea.Cmd {
+	m.appendMessage("the server doesn't provide a room listing yet; use /join <room> to switch")
+
+	return nil
+}
+
+func cmdJoin(m *Model, args []string) tea.Cmd {
+	if len(args) < 1 {
+		m.err = fmt.Errorf("/join requires a room name")
+		return nil
+	}
+
+	room := args[0]
+	newURL := withRoom(m.wsURL, room)
+
+	m.err = nil
+
+	// Cancel before closing, so the old receive loop sees its ctx cancelled
+	// and exits quietly instead of reporting the close as a read error.
+	if m.cancel != nil {
+		m.cancel()
+	}
+	if m.conn != nil {
+		m.conn.Close(websocket.StatusNormalClosure, "switching rooms")
+	}
+
+	m.wsURL = newURL
+	m.room = room
+	m.conn = nil
+	m.cancel = nil
+	m.messages = nil
+	m.messageCache = nil
+	m.viewport.SetContent("")
+	m.wordBoxGuide = "WAITING ROUND START!"
+	m.wordBox = ""
+	m.textInput.Placeholder = "connecting..."
+
+	return connectToWsServer(m.parentCtx, newURL)
+}
+
+func cmdWho(m *Model, args []string) tea.Cmd {
+	nick := m.nick
+	if nick == "" {
+		nick = "(no nick set)"
+	}
+
+	m.appendMessage(fmt.Sprintf(
+		"you are %s in room %s; the server doesn't report other players yet",
+		nick,
+		m.room,
+	))
+
+	return nil
+}
+
+// cmdHistory scrolls the viewport so its top sits n wrapped lines before the
+// end of the scrollback (default: one viewport height), rather than
+// re-printing them, since the full scrollback is already there to see.
+func cmdHistory(m *Model, args []string) tea.Cmd {
+	n := m.viewport.Height
+	if len(args) > 0 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed < 1 {
+			m.err = fmt.Errorf("/history expects a positive number")
+			return nil
+		}
+		n = parsed
+	}
+
+	m.viewport.SetYOffset(max(0, m.viewport.TotalLineCount()-n))
+
+	return nil
+}
+
+func cmdClear(m *Model, args []string) tea.Cmd {
+	m.messages = nil
+	m.messageCache = nil
+	m.viewport.SetContent("")
+
+	return nil
+}
+
+func cmdExit(m *Model, args []string) tea.Cmd {
+	if m.cancel != nil {
+		m.cancel()
+	}
+
+	return tea.Quit
+}
+
+// roomFromURL extracts the trailing path segment (the room id) from a websocket URL.
+func roomFromURL(wsURL string) string {
+	idx := strings.LastIndex(wsURL, "/")
+	if idx == -1 {
+		return wsURL
+	}
+
+	return wsURL[idx+1:]
+}
+
+// withRoom replaces the trailing path segment of wsURL with room.
+func withRoom(wsURL string, room string) string {
+	idx := strings.LastIndex(wsURL, "/")
+	if idx == -1 {
+		return wsURL
+	}
+
+	return wsURL[:idx+1] + room
+}
+
+func renderHelp() string {
+	lines := make([]string, 0, len(helpEntries))
+	for _, e := range helpEntries {
+		lines = append(lines, fmt.Sprintf("%-16s %s", e.usage, e.description))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func displayOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+var headerStyle = lipgloss.NewStyle().
+	Background(lipgloss.Color("26")).
+	Foreground(lipgloss.Color("255")).
+	Align(lipgloss.Center).
+	PaddingTop(1)
+var mainStyle = lipgloss.NewStyle().
+	Background(lipgloss.Color("26")).
+	Foreground(lipgloss.Color("255")).
+	Align(lipgloss.Center).
+	PaddingBottom(1).
+	Bold(true)
+var messageTopBottomStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("68"))
+var messageStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder(), false, true).
+	BorderForeground(lipgloss.Color("68")).
+	PaddingLeft(1).
+	PaddingRight(1)
+var errorStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("9"))
+var hotkeyStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("8")).
+	Bold(true)
+var hotkeyTooltipStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("8"))
+var statusLineStyle = lipgloss.NewStyle().
+	Background(lipgloss.Color("26")).
+	Foreground(lipgloss.Color("255")).
+	Align(lipgloss.Center)
+var helpPanelStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder(), false, true).
+	BorderForeground(lipgloss.Color("68")).
+	PaddingLeft(1).
+	PaddingRight(1)
+
+func (m Model) View() string {
+	timeout := m.timer.Timeout.Round(100 * time.Millisecond)
+
+	s := "\n"
+
+	s += statusLineStyle.Width(m.width).Render(fmt.Sprintf(
+		"room: %s  nick: %s",
+		displayOrDash(m.room),
+		displayOrDash(m.nick),
+	))
+	s += "\n"
+
+	if m.timer.Timedout() {
+		s += headerStyle.Width(m.width).Render(m.wordBoxGuide)
+	} else {
+		s += headerStyle.Width(m.width).Render(fmt.Sprintf(
+			"%s - %d.%ds",
+			m.wordBoxGuide,
+			timeout.Milliseconds()/1000,
+			(timeout.Milliseconds()-timeout.Milliseconds()/1000*1000)/100,
+		))
+	}
+	s += "\n"
+
+	s += mainStyle.Width(m.width).Render(fmt.Sprintf("'%s'", m.wordBox))
+	s += "\n"
+
+	s += messageTopBottomStyle.Render(fmt.Sprintf("╭%s╮", strings.Repeat("─", m.viewport.Width)))
+	s += "\n"
+	if m.showHelp {
+		s += helpPanelStyle.Width(m.viewport.Width).Height(m.viewport.Height).Render(renderHelp())
+	} else {
+		s += messageStyle.Width(m.viewport.Width).Render(m.viewport.View())
+	}
+	s += "\n"
+	s += messageTopBottomStyle.Render(fmt.Sprintf("╰%s╯", strings.Repeat("─", m.viewport.Width)))
+	s += "\n"
+
+	s += m.textInput.View()
+	s += "\n"
+
+	if m.err != nil {
+		s += errorStyle.Width(m.width).Render(m.err.Error())
+		s += "\n"
+	}
+
+	s += "\n"
+	s += hotkeyStyle.Render("Ctrl+C")
+	s += hotkeyTooltipStyle.Render(" exit  ")
+	s += hotkeyStyle.Render("Ctrl+E")
+	s += hotkeyTooltipStyle.Render(" clear errors  ")
+	s += hotkeyStyle.Render("Tab")
+	s += hotkeyTooltipStyle.Render(" scroll focus  ")
+	s += hotkeyStyle.Render("Ctrl+T")
+	s += hotkeyTooltipStyle.Render(" timestamps  ")
+	s += hotkeyStyle.Render("/help")
+	s += hotkeyTooltipStyle.Render(" list commands")
+	s += "\n"
+
+	// Send the UI for rendering
+	return s
+}
+
+// connectToWsServer dials link, deriving its connection context from
+// parentCtx so the connection is torn down when parentCtx is (e.g. an
+// ssh.Session ending) even if nothing in Update ever calls cancel itself.
+func connectToWsServer(parentCtx context.Context, link string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithCancel(parentCtx)
+
+		conn, _, err := websocket.Dial(ctx, link, nil)
+		if err != nil {
+			cancel()
+			return errMsg{fmt.Errorf("websocket.Dial: %v", err)}
+		}
+
+		return initConnMsg{ctx, cancel, conn}
+	}
+}
+
+// receiveFromWsServer runs in its own goroutine for the lifetime of conn,
+// decoding each frame and handing it to send. It returns once the read
+// fails, which happens on a closed connection or ctx cancellation. A read
+// failure caused by our own ctx being cancelled (e.g. a deliberate /join
+// switching connections) is not reported as an error.
+func receiveFromWsServer(ctx context.Context, conn *websocket.Conn, send func(tea.Msg)) {
+	for {
+		var env common.Envelope
+
+		if err := wsjson.Read(ctx, conn, &env); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			send(wsErrMsg{fmt.Errorf("wsjson.Read: %v", err)})
+			return
+		}
+
+		send(decodeWsEnvelope(env))
+	}
+}
+
+func decodeWsEnvelope(env common.Envelope) tea.Msg {
+	if err := common.CheckSchemaVersion(env.SchemaVersion); err != nil {
+		return wsErrMsg{err}
+	}
+
+	switch env.Type {
+	case "ChatMessage":
+		var content common.ChatMessage
+		if err := json.Unmarshal(env.Content, &content); err != nil {
+			return wsErrMsg{fmt.Errorf("json.Unmarshal ChatMessage: %v", err)}
+		}
+		return wsChatMsg{content.Content}
+	case "OngoingRoundInfo":
+		var content common.OngoingRoundInfo
+		if err := json.Unmarshal(env.Content, &content); err != nil {
+			return wsErrMsg{fmt.Errorf("json.Unmarshal OngoingRoundInfo: %v", err)}
+		}
+		return wsOngoingRoundInfoMsg{content}
+	case "FinishedRoundInfo":
+		var content common.FinishedRoundInfo
+		if err := json.Unmarshal(env.Content, &content); err != nil {
+			return wsErrMsg{fmt.Errorf("json.Unmarshal FinishedRoundInfo: %v", err)}
+		}
+		return wsFinishedRoundInfoMsg{content}
+	case "FinishedGame":
+		var content common.FinishedGame
+		if err := json.Unmarshal(env.Content, &content); err != nil {
+			return wsErrMsg{fmt.Errorf("json.Unmarshal FinishedGame: %v", err)}
+		}
+		return wsFinishedGameMsg{}
+	case "PongMessage":
+		var content common.Pong
+		if err := json.Unmarshal(env.Content, &content); err != nil {
+			return wsErrMsg{fmt.Errorf("json.Unmarshal Pong: %v", err)}
+		}
+		return wsPongMsg{}
+	default:
+		return wsErrMsg{fmt.Errorf("unknown message type: %s", env.Type)}
+	}
+}
+
+func sendToWsServer(ctx context.Context, conn *websocket.Conn, msg string) tea.Cmd {
+	return func() tea.Msg {
+		if msg == "/ping" {
+			return errMsg{fmt.Errorf(
+				"don't ping manually! this is handled automatically by the client",
+			)}
+		}
+
+		err := conn.Write(ctx, websocket.MessageText, []byte(msg))
+		if err != nil {
+			return errMsg{fmt.Errorf("c.Write: %v", err)}
+		}
+
+		return successSentMsg{}
+	}
+}
+
+// periodicallyPingWsServer runs in its own goroutine for the lifetime of
+// conn, writing a ping on every tick until ctx is cancelled or the write fails.
+func periodicallyPingWsServer(ctx context.Context, conn *websocket.Conn, send func(tea.Msg)) {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := conn.Write(ctx, websocket.MessageText, []byte("/ping")); err != nil {
+				send(wsErrMsg{fmt.Errorf("c.Write: %v", err)})
+				return
+			}
+		}
+	}
+}